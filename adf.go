@@ -11,26 +11,108 @@ import (
 )
 
 const (
-	LPenalty      = 0.0001 // L penalty to pass to ridge regression
-	DefaultPValue = -3.45  // Test p-value threshold
+	LPenalty = 0.0001 // L penalty to pass to ridge regression
+
+	// DefaultPValue is the default significance level used by IsStationary.
+	//
+	// Before the introduction of PValue/CriticalValues this constant held a
+	// raw test-statistic cutoff (-3.45) and PValueThreshold was compared
+	// directly against ADF.Statistic. Both now represent a significance
+	// level in (0, 1], compared against PValue(). Callers still passing the
+	// old raw-statistic convention to NewADF are caught below and fall back
+	// to this default rather than getting a threshold IsStationary can
+	// never satisfy.
+	DefaultPValue = 0.05
+)
+
+// RegressionMode selects which deterministic terms are included in the ADF
+// test regression.
+type RegressionMode int
+
+const (
+	// None de-means the series and includes no deterministic terms in the
+	// regression. This is the original behaviour of this package.
+	None RegressionMode = iota
+	// Constant appends an intercept column to the regression design matrix
+	// (the "c" specification).
+	Constant
+	// ConstantTrend appends an intercept and a linear time-trend column to
+	// the regression design matrix (the "ct" specification).
+	ConstantTrend
+)
+
+// LagSelectionMode selects how the number of augmenting lagged-difference
+// terms is chosen.
+type LagSelectionMode int
+
+const (
+	// Fixed uses the Lag field as given (or the cube-root default chosen by
+	// NewADF) without further selection. This is the original behaviour of
+	// this package.
+	Fixed LagSelectionMode = iota
+	// AIC picks, from [0, MaxLag], the lag minimizing Akaike's information
+	// criterion.
+	AIC
+	// BIC picks, from [0, MaxLag], the lag minimizing the Bayesian
+	// information criterion.
+	BIC
+	// TStat implements Ng-Perron's general-to-specific rule: starting from
+	// MaxLag, the lag is decremented while the t-statistic on the last
+	// lagged-difference coefficient is insignificant at the 10% level
+	// (|t| <= 1.645), stopping at the first significant lag or at 0.
+	TStat
+)
+
+// Estimator selects how the ADF test regression is fit and its standard
+// errors computed.
+type Estimator int
+
+const (
+	// OLSEstimator fits the regression by ordinary least squares with
+	// conventional homoskedastic standard errors. It is the zero-value
+	// default. The design and lagged-difference matrices come from the
+	// Workspace as for every estimator; fitOLS itself takes no Workspace
+	// and allocates its own scratch on each call.
+	OLSEstimator Estimator = iota
+	// OLSHACEstimator fits the regression by ordinary least squares and
+	// computes Newey-West heteroskedasticity-and-autocorrelation-consistent
+	// standard errors, truncated at HACLag with Bartlett weights.
+	OLSHACEstimator
+	// RidgeEstimator fits the regression by ridge regression with a small
+	// penalty (LPenalty), as this package always has. Ridge shrinks the
+	// standard errors along with the coefficients, biasing the reported
+	// statistic, so RidgeEstimator is kept only for backwards compatibility
+	// and must be selected explicitly. Like fitOLS, its solver takes no
+	// Workspace and allocates its own scratch on each call.
+	RidgeEstimator
 )
 
 // An instance of an ADF test
 type ADF struct {
-	Series          []float64 // The time series to test
-	PValueThreshold float64   // The p-value threshold for the test
-	Statistic       float64   // The test statistic
-	Lag             int       // The lag to use when running the test
+	Series          []float64        // The time series to test
+	PValueThreshold float64          // The significance level used by IsStationary, e.g. 0.05 (not a raw statistic cutoff)
+	Statistic       float64          // The test statistic
+	Lag             int              // The lag to use when running the test; overwritten by Run if LagSelection is not Fixed
+	Regression      RegressionMode   // The deterministic terms to include in the regression
+	LagSelection    LagSelectionMode // How Lag is chosen before the test regression is run
+	MaxLag          int              // The largest lag considered by the AIC, BIC and TStat selection modes
+	Estimator       Estimator        // How the test regression is fit and its standard errors computed
+	HACLag          int              // Truncation lag for OLSHACEstimator's Newey-West standard errors
+
+	nobs int // The regression sample size, recorded by Run for use by PValue/CriticalValues
 }
 
 // New creates and returns a new ADF test.
 func NewADF(series []float64, pvalue float64, lag int) *ADF {
-	if pvalue == 0 {
+	// pvalue is a significance level in (0, 1]; reject the pre-MacKinnon
+	// convention of a raw negative statistic cutoff (e.g. -3.45) instead of
+	// silently installing a threshold IsStationary can never satisfy.
+	if pvalue <= 0 {
 		pvalue = DefaultPValue
 	}
 
 	if lag < 0 {
-		lag = int(math.Floor(math.Cbrt(float64(len(series)))))
+		lag = defaultLag(len(series))
 	}
 
 	newSeries := make([]float64, len(series))
@@ -39,60 +121,353 @@ func NewADF(series []float64, pvalue float64, lag int) *ADF {
 	return &ADF{Series: newSeries, PValueThreshold: pvalue, Lag: lag}
 }
 
+// defaultLag returns the cube-root-of-n lag used whenever an explicit lag
+// isn't given, by NewADF and by RunBatch for a negative Options.Lag.
+func defaultLag(n int) int {
+	return int(math.Floor(math.Cbrt(float64(n))))
+}
+
 // Run runs the Augmented Dickey-Fuller test.
 func (adf *ADF) Run() {
-	series := adf.Series
-	mean := stat.Mean(series, nil)
+	series := adf.regressionSeries()
+	ws := NewWorkspace()
+
+	if adf.LagSelection != Fixed {
+		adf.Lag = adf.selectLag(series, ws)
+	}
+
+	result := fitADF(series, adf.Lag, adf.Regression, adf.Estimator, adf.HACLag, ws)
+	adf.Statistic = result.statistic
+	adf.nobs = result.nobs
+}
+
+// regressionSeries returns the series to build the test regression from,
+// de-meaning it first when no deterministic terms will be included.
+func (adf ADF) regressionSeries() []float64 {
+	if adf.Regression != None {
+		return adf.Series
+	}
+
+	mean := stat.Mean(adf.Series, nil)
+	if mean == 0.0 {
+		return adf.Series
+	}
+
+	demeaned := make([]float64, len(adf.Series))
+	for i, v := range adf.Series {
+		demeaned[i] = v - mean
+	}
+	return demeaned
+}
+
+// selectLag chooses the number of augmenting lagged-difference terms
+// according to adf.LagSelection and adf.MaxLag.
+func (adf ADF) selectLag(series []float64, ws *Workspace) int {
+	switch adf.LagSelection {
+	case AIC:
+		return selectLagByIC(series, adf, ws, func(rss, n float64, k int) float64 {
+			return n*math.Log(rss/n) + 2*float64(k)
+		})
+	case BIC:
+		return selectLagByIC(series, adf, ws, func(rss, n float64, k int) float64 {
+			return n*math.Log(rss/n) + float64(k)*math.Log(n)
+		})
+	case TStat:
+		return selectLagByTStat(series, adf, ws)
+	default:
+		return adf.Lag
+	}
+}
+
+// selectLagByIC fits the ADF regression for every lag in [0, adf.MaxLag] and
+// returns the lag minimizing criterion(rss, n, k). adf.MaxLag is first
+// clamped to the series' feasible range so an overambitious MaxLag can't
+// drive fitADF into an unsolvable (or degenerate, NaN-producing) design
+// matrix. Every candidate lag is fit over the same number of observations -
+// trimming off however many leading points a smaller k doesn't need - so
+// rss and n aren't mechanically shrinking together as k grows, which would
+// otherwise bias AIC/BIC toward longer lags regardless of fit quality.
+func selectLagByIC(series []float64, adf ADF, ws *Workspace, criterion func(rss, n float64, k int) float64) int {
+	best, bestScore := 0, math.Inf(1)
+
+	maxLag := clampMaxLag(len(series), adf.MaxLag, adf.Regression)
+
+	for k := 0; k <= maxLag; k++ {
+		trimmed := series[maxLag-k:]
+		if !regressionFeasible(len(trimmed), k, adf.Regression) {
+			break
+		}
+
+		result := fitADF(trimmed, k, adf.Regression, adf.Estimator, adf.HACLag, ws)
+		score := criterion(result.rss, float64(result.nobs), k)
+
+		if score < bestScore {
+			best, bestScore = k, score
+		}
+	}
+
+	return best
+}
+
+// selectLagByTStat implements Ng-Perron's general-to-specific rule, starting
+// at adf.MaxLag (clamped to the series' feasible range, see clampMaxLag) and
+// decrementing while the last lagged-difference coefficient is
+// statistically insignificant.
+func selectLagByTStat(series []float64, adf ADF, ws *Workspace) int {
+	maxLag := clampMaxLag(len(series), adf.MaxLag, adf.Regression)
+
+	for k := maxLag; k > 0; k-- {
+		if !regressionFeasible(len(series), k, adf.Regression) {
+			continue
+		}
+
+		result := fitADF(series, k, adf.Regression, adf.Estimator, adf.HACLag, ws)
+		if math.Abs(result.lastLagTStat) > 1.645 {
+			return k
+		}
+	}
+
+	return 0
+}
+
+// regressionFeasible reports whether fitADF's design matrix for a
+// length-n series at the given lag and regression mode has at least one
+// residual degree of freedom (nobs > ncols), i.e. whether it can be fit at
+// all without producing a zero-row or rank-deficient design.
+func regressionFeasible(n, lag int, mode RegressionMode) bool {
+	k := lag + 1
+	nobs := (n - 1) - k + 1
+	ncols := k + deterministicCols(mode)
+	return nobs > ncols
+}
 
-	if mean != 0.0 {
-		for i, v := range series {
-			series[i] = v - mean
+// clampMaxLag returns the largest lag in [0, maxLag] for which
+// regressionFeasible holds for a length-n series, so that lag selection
+// never considers a lag fitADF can't actually fit.
+func clampMaxLag(n, maxLag int, mode RegressionMode) int {
+	feasible := 0
+	for lag := 0; lag <= maxLag; lag++ {
+		if !regressionFeasible(n, lag, mode) {
+			break
 		}
+		feasible = lag
 	}
+	return feasible
+}
 
+// fitResult holds the outputs of a single ADF test regression needed by Run
+// and by lag selection.
+type fitResult struct {
+	statistic    float64 // beta[0] / sd[0], the Dickey-Fuller test statistic
+	lastLagTStat float64 // t-statistic on the last lagged-difference coefficient, or 0 if lag == 0
+	rss          float64 // residual sum of squares
+	nobs         int     // regression sample size
+}
+
+// fitADF builds the ADF test regression for the given lag and regression
+// mode and fits it using est (with, for OLSHACEstimator, a Newey-West
+// truncation lag of hacLag), returning the statistic together with the
+// quantities needed for lag-order selection. ws supplies reusable scratch
+// matrices; pass NewWorkspace() for a one-off fit.
+func fitADF(series []float64, lag int, mode RegressionMode, est Estimator, hacLag int, ws *Workspace) fitResult {
 	n := len(series) - 1
 	y := diff(series)
-	lag := adf.Lag
 	k := lag + 1
 
-	z := laggedMatrix(y, k)
+	zRows, zCols := len(y)-k+1, k
+	z := laggedMatrix(ws.denseOf(&ws.z, zRows, zCols), y, k)
 
 	zcol1 := mat.Col(nil, 0, z)
 	xt1 := series[k-1 : n]
 	r, c := z.Dims()
 
-	var design *mat.Dense
+	nobs := n - k + 1
+	ncols := k + deterministicCols(mode)
+
+	design := ws.denseOf(&ws.design, nobs, ncols)
+	design.SetCol(0, xt1)
 
 	if k > 1 {
 		yt1 := view(z, 0, 1, r, c-1)
-		design = mat.NewDense(n-k+1, k, nil)
-		design.SetCol(0, xt1)
-
 		_, c = yt1.Dims()
 
 		for i := 0; i < c; i++ {
 			design.SetCol(1+i, mat.Col(nil, i, yt1))
 		}
+	}
+
+	setDeterministicCols(design, k, mode)
+
+	regressY := mat.NewVecDense(len(zcol1), zcol1)
 
+	var beta, sd []float64
+	if est == OLSEstimator || est == OLSHACEstimator {
+		beta, sd = fitOLS(design, regressY, est, hacLag)
 	} else {
-		design = mat.NewDense(n-k+1, 1, nil)
-		design.SetCol(0, xt1)
+		rr := NewRidge(design, regressY, LPenalty)
+		rr.Regress()
+		beta = rr.Coefficients.RawVector().Data
+		sd = rr.StdErrs
 	}
 
-	regressY := mat.NewVecDense(len(zcol1), zcol1)
+	betaVec := mat.NewVecDense(len(beta), beta)
 
-	rr := NewRidge(design, regressY, LPenalty)
-	rr.Regress()
+	fitted := ws.vecOf(&ws.fitted, nobs)
+	fitted.MulVec(design, betaVec)
 
-	beta := rr.Coefficients.RawVector().Data
-	sd := rr.StdErrs
+	resid := ws.vecOf(&ws.resid, nobs)
+	resid.SubVec(regressY, fitted)
 
-	adf.Statistic = beta[0] / sd[0]
+	result := fitResult{
+		statistic: beta[0] / sd[0],
+		rss:       mat.Dot(resid, resid),
+		nobs:      nobs,
+	}
+
+	if k > 1 {
+		result.lastLagTStat = beta[k-1] / sd[k-1]
+	}
+
+	return result
+}
+
+// deterministicCols returns the number of deterministic-term columns that
+// mode appends to the regression design matrix.
+func deterministicCols(mode RegressionMode) int {
+	switch mode {
+	case Constant:
+		return 1
+	case ConstantTrend:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// setDeterministicCols fills the deterministic-term columns of design,
+// starting at column offset, according to mode: an all-ones column for
+// Constant, plus a 1..n time-index column for ConstantTrend.
+func setDeterministicCols(design *mat.Dense, offset int, mode RegressionMode) {
+	if mode == None {
+		return
+	}
+
+	rows, _ := design.Dims()
+	ones := make([]float64, rows)
+	for i := range ones {
+		ones[i] = 1
+	}
+	design.SetCol(offset, ones)
+
+	if mode == ConstantTrend {
+		trend := make([]float64, rows)
+		for i := range trend {
+			trend[i] = float64(i + 1)
+		}
+		design.SetCol(offset+1, trend)
+	}
+}
+
+// mackinnonCoeffs holds MacKinnon's (1994, 2010) response-surface regression
+// coefficients (β∞, β1, β2, β3), one set per significance level, used to
+// approximate the finite-sample critical value of the ADF statistic via
+//
+//	cv(n) = β∞ + β1/n + β2/n² + β3/n³
+var mackinnonCoeffs = map[RegressionMode]map[string][4]float64{
+	None: {
+		"1%":  {-2.56574, -2.2358, -3.627, 0},
+		"5%":  {-1.94100, -0.2686, -3.365, 0},
+		"10%": {-1.61682, -0.2656, -1.521, 0},
+	},
+	Constant: {
+		"1%":  {-3.43035, -6.5393, -16.786, -79.433},
+		"5%":  {-2.86154, -2.8903, -4.234, -40.040},
+		"10%": {-2.56677, -1.5384, -2.809, 0},
+	},
+	ConstantTrend: {
+		"1%":  {-3.95877, -9.0531, -28.428, -134.155},
+		"5%":  {-3.41049, -4.3904, -9.036, -45.374},
+		"10%": {-3.12705, -2.5856, -3.925, -22.380},
+	},
+}
+
+// significanceLevels lists the levels tabulated in mackinnonCoeffs, in
+// descending order of critical value magnitude.
+var significanceLevels = []string{"1%", "5%", "10%"}
+
+// CriticalValues returns the approximate finite-sample {1%, 5%, 10%}
+// critical values for the ADF statistic under the test's regression mode, at
+// a sample size of n.
+func (adf ADF) CriticalValues(n int) map[string]float64 {
+	cv := make(map[string]float64, len(significanceLevels))
+	fn := float64(n)
+
+	for _, level := range significanceLevels {
+		c := mackinnonCoeffs[adf.Regression][level]
+		cv[level] = c[0] + c[1]/fn + c[2]/(fn*fn) + c[3]/(fn*fn*fn)
+	}
+
+	return cv
+}
+
+// PValue returns an approximate MacKinnon p-value for the computed test
+// statistic. The finite-sample critical values at the 1%, 5% and 10% levels
+// are used as nodes for a local quadratic interpolation; statistics beyond
+// the outermost nodes are handled by linear extrapolation from the nearest
+// pair of nodes.
+func (adf ADF) PValue() float64 {
+	n := adf.nobs
+	if n == 0 {
+		n = len(adf.Series)
+	}
+
+	cv := adf.CriticalValues(n)
+	cv1, cv5, cv10 := cv["1%"], cv["5%"], cv["10%"]
+	x := adf.Statistic
+
+	switch {
+	case x <= cv1:
+		return clampProb(linearInterp(cv1, 0.01, cv5, 0.05, x))
+	case x >= cv10:
+		return clampProb(linearInterp(cv5, 0.05, cv10, 0.10, x))
+	default:
+		return clampProb(quadraticInterp(cv1, 0.01, cv5, 0.05, cv10, 0.10, x))
+	}
+}
+
+// linearInterp linearly interpolates (or extrapolates) the value of y at x
+// given two known points (x0, y0) and (x1, y1).
+func linearInterp(x0, y0, x1, y1, x float64) float64 {
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
+
+// quadraticInterp fits the unique quadratic through (x0, y0), (x1, y1) and
+// (x2, y2) via Lagrange's formula and evaluates it at x.
+func quadraticInterp(x0, y0, x1, y1, x2, y2, x float64) float64 {
+	l0 := (x - x1) * (x - x2) / ((x0 - x1) * (x0 - x2))
+	l1 := (x - x0) * (x - x2) / ((x1 - x0) * (x1 - x2))
+	l2 := (x - x0) * (x - x1) / ((x2 - x0) * (x2 - x1))
+	return y0*l0 + y1*l1 + y2*l2
+}
+
+// clampProb clamps a probability to the [0, 1] range, guarding against
+// overshoot from the interpolation in the extreme tails.
+func clampProb(p float64) float64 {
+	switch {
+	case p < 0:
+		return 0
+	case p > 1:
+		return 1
+	default:
+		return p
+	}
 }
 
-// IsStationary returns true if the tested time series is stationary.
+// IsStationary returns true if the tested time series is stationary, i.e. if
+// the MacKinnon p-value for the test statistic is at or below
+// PValueThreshold.
 func (adf ADF) IsStationary() bool {
-	return adf.Statistic < adf.PValueThreshold
+	return adf.PValue() <= adf.PValueThreshold
 }
 
 func diff(x []float64) []float64 {
@@ -103,16 +478,16 @@ func diff(x []float64) []float64 {
 	return y
 }
 
-func laggedMatrix(series []float64, lag int) *mat.Dense {
-	r, c := len(series)-lag+1, lag
-	m := mat.NewDense(r, c, nil)
+// laggedMatrix fills dst with the lagged columns of series and returns it.
+func laggedMatrix(dst *mat.Dense, series []float64, lag int) *mat.Dense {
+	r, c := dst.Dims()
 
 	for j := 0; j < c; j++ {
 		for i := 0; i < r; i++ {
-			m.Set(i, j, series[lag-j-1+i])
+			dst.Set(i, j, series[lag-j-1+i])
 		}
 	}
-	return m
+	return dst
 }
 
 func view(m *mat.Dense, i, j, r, c int) mat.Matrix {
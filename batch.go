@@ -0,0 +1,105 @@
+package adf
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Options configures a batch of ADF tests run by RunBatch. Its fields mirror
+// the corresponding ADF fields and are applied identically to every series
+// in the batch.
+type Options struct {
+	PValueThreshold float64
+	Lag             int
+	Regression      RegressionMode
+	LagSelection    LagSelectionMode
+	MaxLag          int
+	Estimator       Estimator
+	HACLag          int
+}
+
+// Result is the outcome of a single ADF test run as part of a batch.
+type Result struct {
+	Statistic    float64
+	Lag          int
+	PValue       float64
+	IsStationary bool
+}
+
+// RunBatch runs the ADF test across many series concurrently, using a
+// worker pool sized to runtime.GOMAXPROCS. Each worker reuses a single
+// Workspace across the series it processes, so series that share a length
+// (as is typical when scraping many KPIs on the same interval) amortise
+// their design-matrix allocations instead of paying for them on every
+// series.
+func RunBatch(series [][]float64, opts Options) []Result {
+	results := make([]Result, len(series))
+	if len(series) == 0 {
+		return results
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(series) {
+		workers = len(series)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ws := NewWorkspace()
+			for idx := range jobs {
+				results[idx] = runOne(series[idx], opts, ws)
+			}
+		}()
+	}
+
+	for i := range series {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg.Wait()
+	return results
+}
+
+// runOne runs a single ADF test from opts against series, using ws as
+// scratch space.
+func runOne(series []float64, opts Options, ws *Workspace) Result {
+	adf := &ADF{
+		Series:          series,
+		PValueThreshold: opts.PValueThreshold,
+		Lag:             opts.Lag,
+		Regression:      opts.Regression,
+		LagSelection:    opts.LagSelection,
+		MaxLag:          opts.MaxLag,
+		Estimator:       opts.Estimator,
+		HACLag:          opts.HACLag,
+	}
+	if adf.PValueThreshold <= 0 {
+		adf.PValueThreshold = DefaultPValue
+	}
+	if adf.Lag < 0 {
+		adf.Lag = defaultLag(len(series))
+	}
+
+	regressionSeries := adf.regressionSeries()
+	if adf.LagSelection != Fixed {
+		adf.Lag = adf.selectLag(regressionSeries, ws)
+	}
+
+	result := fitADF(regressionSeries, adf.Lag, adf.Regression, adf.Estimator, adf.HACLag, ws)
+	adf.Statistic = result.statistic
+	adf.nobs = result.nobs
+
+	return Result{
+		Statistic:    adf.Statistic,
+		Lag:          adf.Lag,
+		PValue:       adf.PValue(),
+		IsStationary: adf.IsStationary(),
+	}
+}
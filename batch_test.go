@@ -0,0 +1,51 @@
+package adf
+
+import "testing"
+
+func TestRunBatchEmpty(t *testing.T) {
+	got := RunBatch(nil, Options{})
+	if len(got) != 0 {
+		t.Errorf("RunBatch(nil, ...) = %v, want empty", got)
+	}
+}
+
+func TestRunBatchNegativeLagUsesDefault(t *testing.T) {
+	series := make([]float64, 20)
+	for i := range series {
+		series[i] = float64(i%5) - float64(i%3)
+	}
+
+	got := RunBatch([][]float64{series}, Options{Lag: -1})
+	if len(got) != 1 {
+		t.Fatalf("RunBatch returned %d results, want 1", len(got))
+	}
+	if got[0].Lag != defaultLag(len(series)) {
+		t.Errorf("RunBatch with Options.Lag: -1 gave Lag %d, want defaultLag(%d) = %d", got[0].Lag, len(series), defaultLag(len(series)))
+	}
+}
+
+func TestRunBatchMatchesRun(t *testing.T) {
+	series := []float64{1, 2, 1.5, 3, 2.5, 4, 3.2, 5, 4.1, 6}
+	opts := Options{Regression: Constant, LagSelection: Fixed, Lag: 1, Estimator: OLSEstimator}
+
+	batch := RunBatch([][]float64{series, series}, opts)
+
+	adf := &ADF{Series: series, Regression: opts.Regression, LagSelection: opts.LagSelection, Lag: opts.Lag, Estimator: opts.Estimator, PValueThreshold: DefaultPValue}
+	adf.Run()
+
+	for i, r := range batch {
+		if r.Statistic != adf.Statistic {
+			t.Errorf("batch[%d].Statistic = %v, want %v (matching ADF.Run directly)", i, r.Statistic, adf.Statistic)
+		}
+	}
+}
+
+func TestRunBatchDefaultEstimatorIsOLS(t *testing.T) {
+	// Options{} leaves Estimator at its zero value, which must be
+	// OLSEstimator so callers that don't set it get an OLS fit rather than
+	// the ridge fit.
+	var zero Options
+	if zero.Estimator != OLSEstimator {
+		t.Errorf("Options{}.Estimator zero value = %v, want OLSEstimator", zero.Estimator)
+	}
+}
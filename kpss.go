@@ -0,0 +1,138 @@
+package adf
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// KPSSMode selects the deterministic component a series is detrended
+// against before computing the KPSS statistic.
+type KPSSMode int
+
+const (
+	// Level detrends against a constant only, testing the null of level
+	// stationarity.
+	Level KPSSMode = iota
+	// Trend detrends against a constant and a linear time trend, testing
+	// the null of trend stationarity.
+	Trend
+)
+
+// kpssCriticalValues holds the built-in KPSS critical values at the
+// 10%/5%/2.5%/1% levels, from Kwiatkowski et al. (1992), Table 1.
+var kpssCriticalValues = map[KPSSMode]map[string]float64{
+	Level: {"10%": 0.347, "5%": 0.463, "2.5%": 0.574, "1%": 0.739},
+	Trend: {"10%": 0.119, "5%": 0.146, "2.5%": 0.176, "1%": 0.216},
+}
+
+// KPSS is an instance of a Kwiatkowski-Phillips-Schmidt-Shin stationarity
+// test. It complements ADF: its null hypothesis is stationarity rather than
+// a unit root, so the two tests are routinely run together.
+type KPSS struct {
+	Series     []float64 // The time series to test
+	Regression KPSSMode  // The deterministic component to detrend against
+	Lag        int       // The truncation lag for the Newey-West long-run variance estimator
+	Statistic  float64   // The test statistic, eta
+}
+
+// NewKPSS creates and returns a new KPSS test.
+func NewKPSS(series []float64, regression KPSSMode, lag int) *KPSS {
+	newSeries := make([]float64, len(series))
+	copy(newSeries, series)
+
+	return &KPSS{Series: newSeries, Regression: regression, Lag: lag}
+}
+
+// Run runs the KPSS test.
+func (k *KPSS) Run() {
+	n := len(k.Series)
+	resid := detrend(k.Series, k.Regression)
+
+	partialSum := 0.0
+	var sumSq float64
+
+	for _, e := range resid {
+		partialSum += e
+		sumSq += partialSum * partialSum
+	}
+
+	variance := neweyWestVariance(resid, k.Lag)
+
+	k.Statistic = sumSq / (float64(n) * float64(n) * variance)
+}
+
+// CriticalValues returns the {10%, 5%, 2.5%, 1%} critical values for the
+// test's regression mode.
+func (k KPSS) CriticalValues() map[string]float64 {
+	return kpssCriticalValues[k.Regression]
+}
+
+// IsStationary returns true if the tested series is stationary, i.e. if the
+// statistic is below the 5% critical value. KPSS's null hypothesis is
+// stationarity, the opposite of ADF's, so unlike ADF.IsStationary the
+// series is rejected as stationary when the statistic is too large rather
+// than too small.
+func (k KPSS) IsStationary() bool {
+	return k.Statistic < kpssCriticalValues[k.Regression]["5%"]
+}
+
+// detrend regresses series on a constant (Level) or a constant and a linear
+// trend (Trend) by OLS and returns the residuals.
+func detrend(series []float64, mode KPSSMode) []float64 {
+	n := len(series)
+	cols := 1
+	if mode == Trend {
+		cols = 2
+	}
+
+	design := mat.NewDense(n, cols, nil)
+
+	ones := make([]float64, n)
+	for i := range ones {
+		ones[i] = 1
+	}
+	design.SetCol(0, ones)
+
+	if mode == Trend {
+		trend := make([]float64, n)
+		for i := range trend {
+			trend[i] = float64(i + 1)
+		}
+		design.SetCol(1, trend)
+	}
+
+	y := mat.NewVecDense(n, series)
+
+	var beta mat.VecDense
+	beta.SolveVec(design, y)
+
+	fitted := mat.NewVecDense(n, nil)
+	fitted.MulVec(design, &beta)
+
+	resid := make([]float64, n)
+	for i := range resid {
+		resid[i] = series[i] - fitted.AtVec(i)
+	}
+	return resid
+}
+
+// neweyWestVariance computes the Newey-West heteroskedasticity-and-
+// autocorrelation-consistent long-run variance estimate of resid, truncated
+// at lag with Bartlett weights.
+func neweyWestVariance(resid []float64, lag int) float64 {
+	n := len(resid)
+
+	gamma := func(j int) float64 {
+		var sum float64
+		for t := j; t < n; t++ {
+			sum += resid[t] * resid[t-j]
+		}
+		return sum / float64(n)
+	}
+
+	variance := gamma(0)
+	for j := 1; j <= lag; j++ {
+		weight := 1 - float64(j)/float64(lag+1)
+		variance += 2 * weight * gamma(j)
+	}
+	return variance
+}
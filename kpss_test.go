@@ -0,0 +1,59 @@
+package adf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNeweyWestVarianceNoAutocorrelation(t *testing.T) {
+	resid := []float64{1, -1, 1, -1, 1, -1}
+	got := neweyWestVariance(resid, 0)
+	want := 1.0 // mean(e^2) with no cross terms
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("neweyWestVariance(resid, 0) = %v, want %v", got, want)
+	}
+}
+
+func TestDetrendLevelRemovesConstant(t *testing.T) {
+	series := []float64{5, 5, 5, 5, 5}
+	resid := detrend(series, Level)
+	for i, r := range resid {
+		if math.Abs(r) > 1e-9 {
+			t.Errorf("detrend(Level) residual[%d] = %v, want ~0 for a constant series", i, r)
+		}
+	}
+}
+
+func TestDetrendTrendRemovesLinearTrend(t *testing.T) {
+	series := []float64{1, 2, 3, 4, 5}
+	resid := detrend(series, Trend)
+	for i, r := range resid {
+		if math.Abs(r) > 1e-9 {
+			t.Errorf("detrend(Trend) residual[%d] = %v, want ~0 for a perfectly linear series", i, r)
+		}
+	}
+}
+
+func TestKPSSStationarySeriesHasLowStatistic(t *testing.T) {
+	// A series oscillating around a constant mean should score well below
+	// the 5% critical value for the Level specification.
+	series := []float64{1, -1, 1, -1, 1, -1, 1, -1, 1, -1}
+	k := NewKPSS(series, Level, 2)
+	k.Run()
+
+	if !k.IsStationary() {
+		t.Errorf("KPSS statistic %v should be stationary (below the 5%% critical value %v)", k.Statistic, k.CriticalValues()["5%"])
+	}
+}
+
+func TestKPSSCriticalValuesByMode(t *testing.T) {
+	level := KPSS{Regression: Level}
+	if got := level.CriticalValues()["5%"]; got != 0.463 {
+		t.Errorf("Level 5%% critical value = %v, want 0.463", got)
+	}
+
+	trend := KPSS{Regression: Trend}
+	if got := trend.CriticalValues()["5%"]; got != 0.146 {
+		t.Errorf("Trend 5%% critical value = %v, want 0.146", got)
+	}
+}
@@ -0,0 +1,63 @@
+package adf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewADFDefaultsNegativeLag(t *testing.T) {
+	series := make([]float64, 27)
+	adf := NewADF(series, 0.05, -1)
+	if adf.Lag != defaultLag(27) {
+		t.Errorf("NewADF with a negative lag should use defaultLag(27) = %d, got %d", defaultLag(27), adf.Lag)
+	}
+}
+
+func TestRegressionFeasible(t *testing.T) {
+	// An 8-point series with Regression: None and lag 3 (k=4) leaves
+	// nobs = 8-4 = 4 against ncols = 4: no residual degrees of freedom,
+	// infeasible.
+	if regressionFeasible(8, 3, None) {
+		t.Errorf("regressionFeasible(8, 3, None) = true, want false")
+	}
+	// lag 2 (k=3) leaves nobs = 8-3 = 5 against ncols = 3: feasible.
+	if !regressionFeasible(8, 2, None) {
+		t.Errorf("regressionFeasible(8, 2, None) = false, want true")
+	}
+}
+
+func TestClampMaxLagMatchesReproCase(t *testing.T) {
+	// Reported repro: an 8-point series with Regression: None and MaxLag: 7
+	// should clamp down to the largest feasible lag (2, since lag 3 is the
+	// first infeasible one) instead of letting lag selection walk into an
+	// unsolvable design.
+	if got := clampMaxLag(8, 7, None); got != 2 {
+		t.Errorf("clampMaxLag(8, 7, None) = %d, want 2", got)
+	}
+}
+
+func TestSelectLagByICStaysWithinFeasibleRange(t *testing.T) {
+	series := []float64{1, 2, 1.5, 3, 2.5, 4, 3.2, 5}
+	adf := ADF{Regression: None, MaxLag: 7, Estimator: OLSEstimator}
+	ws := NewWorkspace()
+
+	bic := func(rss, n float64, k int) float64 {
+		return n*math.Log(rss/n) + float64(k)*math.Log(n)
+	}
+
+	lag := selectLagByIC(series, adf, ws, bic)
+	if lag > clampMaxLag(len(series), adf.MaxLag, adf.Regression) {
+		t.Errorf("selectLagByIC returned lag %d beyond the feasible range", lag)
+	}
+}
+
+func TestSelectLagByTStatStaysWithinFeasibleRange(t *testing.T) {
+	series := []float64{1, 2, 1.5, 3, 2.5, 4, 3.2, 5}
+	adf := ADF{Regression: None, MaxLag: 7, Estimator: OLSEstimator}
+	ws := NewWorkspace()
+
+	lag := selectLagByTStat(series, adf, ws)
+	if lag > clampMaxLag(len(series), adf.MaxLag, adf.Regression) {
+		t.Errorf("selectLagByTStat returned lag %d beyond the feasible range", lag)
+	}
+}
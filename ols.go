@@ -0,0 +1,126 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// fitOLS fits design/y by ordinary least squares and returns the
+// coefficients together with their standard errors: conventional
+// homoskedastic standard errors for OLSEstimator, or Newey-West HAC
+// standard errors truncated at hacLag for OLSHACEstimator.
+func fitOLS(design *mat.Dense, y *mat.VecDense, est Estimator, hacLag int) ([]float64, []float64) {
+	n, c := design.Dims()
+
+	var beta mat.VecDense
+	beta.SolveVec(design, y)
+
+	fitted := mat.NewVecDense(n, nil)
+	fitted.MulVec(design, &beta)
+
+	resid := mat.NewVecDense(n, nil)
+	resid.SubVec(y, fitted)
+
+	xtxInv, ok := invertXtX(design)
+
+	sd := make([]float64, c)
+	switch {
+	case !ok:
+		// X^T X is singular or too ill-conditioned to invert (short series,
+		// high lag, or ConstantTrend's collinear columns are the usual
+		// cause). beta is still whatever SolveVec produced, but standard
+		// errors - and so the reported statistic - can't be trusted, so
+		// signal NaN rather than silently returning wrong numbers.
+		for i := range sd {
+			sd[i] = math.NaN()
+		}
+	case est == OLSHACEstimator:
+		sd = hacStdErrs(design, resid, xtxInv, hacLag)
+	default:
+		rss := mat.Dot(resid, resid)
+		sigma2 := rss / float64(n-c)
+
+		for i := 0; i < c; i++ {
+			sd[i] = math.Sqrt(sigma2 * xtxInv.At(i, i))
+		}
+	}
+
+	betaData := make([]float64, c)
+	for i := 0; i < c; i++ {
+		betaData[i] = beta.AtVec(i)
+	}
+
+	return betaData, sd
+}
+
+// invertXtX returns (X^T X)^-1 for the given design matrix, and false if
+// X^T X is singular or too ill-conditioned to invert.
+func invertXtX(design *mat.Dense) (*mat.Dense, bool) {
+	n, c := design.Dims()
+
+	xtx := mat.NewDense(c, c, nil)
+	for i := 0; i < c; i++ {
+		for j := 0; j < c; j++ {
+			var sum float64
+			for t := 0; t < n; t++ {
+				sum += design.At(t, i) * design.At(t, j)
+			}
+			xtx.Set(i, j, sum)
+		}
+	}
+
+	var inv mat.Dense
+	if err := inv.Inverse(xtx); err != nil {
+		return nil, false
+	}
+	return &inv, true
+}
+
+// hacStdErrs computes Newey-West heteroskedasticity-and-autocorrelation-
+// consistent standard errors for an OLS fit:
+//
+//	Var(beta) = (X^T X)^-1 S (X^T X)^-1
+//	S = sum_t e_t^2 x_t x_t^T
+//	    + sum_{j=1..lag} w_j sum_t (x_t e_t e_{t-j} x_{t-j}^T + transpose)
+//
+// with Bartlett weights w_j = 1 - j/(lag+1).
+func hacStdErrs(design *mat.Dense, resid *mat.VecDense, xtxInv *mat.Dense, lag int) []float64 {
+	n, c := design.Dims()
+
+	s := mat.NewDense(c, c, nil)
+	addOuter := func(t, tj int, weight float64) {
+		for i := 0; i < c; i++ {
+			xti := design.At(t, i)
+			for j := 0; j < c; j++ {
+				s.Set(i, j, s.At(i, j)+weight*xti*design.At(tj, j))
+			}
+		}
+	}
+
+	for t := 0; t < n; t++ {
+		et := resid.AtVec(t)
+		addOuter(t, t, et*et)
+	}
+
+	for j := 1; j <= lag; j++ {
+		weight := 1 - float64(j)/float64(lag+1)
+
+		for t := j; t < n; t++ {
+			cross := weight * resid.AtVec(t) * resid.AtVec(t-j)
+
+			addOuter(t, t-j, cross)
+			addOuter(t-j, t, cross)
+		}
+	}
+
+	var tmp, variance mat.Dense
+	tmp.Mul(xtxInv, s)
+	variance.Mul(&tmp, xtxInv)
+
+	sd := make([]float64, c)
+	for i := 0; i < c; i++ {
+		sd[i] = math.Sqrt(variance.At(i, i))
+	}
+	return sd
+}
@@ -0,0 +1,66 @@
+package adf
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInvertXtXIdentity(t *testing.T) {
+	design := mat.NewDense(4, 2, []float64{1, 0, 0, 1, 1, 0, 0, 1})
+
+	inv, ok := invertXtX(design)
+	if !ok {
+		t.Fatalf("invertXtX failed on a well-conditioned design")
+	}
+
+	// X^T X here is diag(2, 2), so its inverse is diag(0.5, 0.5).
+	if math.Abs(inv.At(0, 0)-0.5) > 1e-9 || math.Abs(inv.At(1, 1)-0.5) > 1e-9 {
+		t.Errorf("invertXtX gave %v, want diag(0.5, 0.5)", mat.Formatted(inv))
+	}
+}
+
+func TestInvertXtXSingularReportsFailure(t *testing.T) {
+	// Two identical columns make X^T X singular.
+	design := mat.NewDense(4, 2, []float64{1, 1, 2, 2, 3, 3, 4, 4})
+
+	_, ok := invertXtX(design)
+	if ok {
+		t.Errorf("invertXtX on a singular design reported ok, want failure")
+	}
+}
+
+func TestFitOLSSingularDesignYieldsNaNStdErrs(t *testing.T) {
+	design := mat.NewDense(4, 2, []float64{1, 1, 2, 2, 3, 3, 4, 4})
+	y := mat.NewVecDense(4, []float64{1, 2, 3, 4})
+
+	_, sd := fitOLS(design, y, OLSEstimator, 0)
+	for i, s := range sd {
+		if !math.IsNaN(s) {
+			t.Errorf("sd[%d] = %v for a singular design, want NaN", i, s)
+		}
+	}
+}
+
+func TestHacStdErrsZeroLagMatchesWhite(t *testing.T) {
+	// With lag 0, hacStdErrs reduces to White's heteroskedasticity-robust
+	// variance xtxInv * (sum_t e_t^2 x_t x_t^T) * xtxInv, with no Bartlett
+	// cross terms.
+	design := mat.NewDense(6, 1, []float64{1, 1, 1, 1, 1, 1})
+	resid := mat.NewVecDense(6, []float64{1, -1, 1, -1, 1, -1})
+
+	xtxInv, ok := invertXtX(design)
+	if !ok {
+		t.Fatalf("invertXtX failed unexpectedly")
+	}
+
+	hac := hacStdErrs(design, resid, xtxInv, 0)
+
+	// xtx = 6, so xtxInv = 1/6; every e_t^2 = 1 and x_t = 1, so
+	// S = 6 and variance = (1/6)*6*(1/6) = 1/6.
+	want := math.Sqrt(1.0 / 6.0)
+	if math.Abs(hac[0]-want) > 1e-9 {
+		t.Errorf("hacStdErrs(lag=0)[0] = %v, want %v", hac[0], want)
+	}
+}
@@ -0,0 +1,144 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// OnlineADF maintains an Augmented Dickey-Fuller test over a sliding window
+// of observations. Each Push folds in the newest regression row and, once
+// the window is full, folds out the oldest one by updating the normal-
+// equation matrices X^T X and X^T y directly, rather than rebuilding and
+// re-solving the full design matrix as Run does. This makes Statistic cheap
+// enough to call after every observation, which suits real-time regime-
+// change monitoring on streaming metrics.
+type OnlineADF struct {
+	Window int // number of raw observations retained in the sliding window
+	Lag    int // number of augmenting lagged-difference terms
+
+	buf   []float64 // fixed-capacity ring buffer of length Window holding the raw observations
+	head  int       // index in buf of the oldest observation currently held
+	count int       // number of valid observations currently in buf (<= Window)
+	rows  int       // number of regression rows currently folded into xtx/xty
+
+	xtx *mat.Dense    // running X^T X over the regression rows currently in the window
+	xty *mat.VecDense // running X^T y over the regression rows currently in the window
+	yty float64       // running sum of y^2 over the regression rows currently in the window
+}
+
+// NewOnlineADF creates and returns a new OnlineADF test over a sliding
+// window of the given size, with lag augmenting lagged-difference terms.
+// window must be greater than 2*(lag+1) for Statistic to produce a value.
+func NewOnlineADF(window, lag int) *OnlineADF {
+	k := lag + 1
+	return &OnlineADF{
+		Window: window,
+		Lag:    lag,
+		buf:    make([]float64, window),
+		xtx:    mat.NewDense(k, k, nil),
+		xty:    mat.NewVecDense(k, nil),
+	}
+}
+
+// Push adds a new observation to the window, updating the running normal
+// equations in place.
+func (o *OnlineADF) Push(x float64) {
+	k := o.Lag + 1
+
+	if o.count == o.Window {
+		oldRow, oldY := adfRow(o.windowRow(0, k+1), k)
+		o.fold(oldRow, oldY, -1)
+
+		o.buf[o.head] = x
+		o.head = (o.head + 1) % o.Window
+	} else {
+		o.buf[o.count] = x
+		o.count++
+	}
+
+	if o.count >= k+1 {
+		newRow, newY := adfRow(o.windowRow(o.count-k-1, k+1), k)
+		o.fold(newRow, newY, 1)
+	}
+}
+
+// windowRow returns length consecutive raw observations from the ring
+// buffer in chronological order, starting offset positions after the
+// oldest observation currently held.
+func (o *OnlineADF) windowRow(offset, length int) []float64 {
+	vals := make([]float64, length)
+	for i := 0; i < length; i++ {
+		vals[i] = o.buf[(o.head+offset+i)%o.Window]
+	}
+	return vals
+}
+
+// fold adds (sign=1) or removes (sign=-1) the contribution of a single
+// regression row to the running X^T X, X^T y and y^T y accumulators.
+func (o *OnlineADF) fold(row []float64, y float64, sign float64) {
+	k := len(row)
+
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			o.xtx.Set(i, j, o.xtx.At(i, j)+sign*row[i]*row[j])
+		}
+		o.xty.SetVec(i, o.xty.AtVec(i)+sign*row[i]*y)
+	}
+
+	o.yty += sign * y * y
+	o.rows += int(sign)
+}
+
+// Statistic solves the current (small) ridge-penalized normal equations and
+// returns the Dickey-Fuller statistic beta[0]/sd[0], matching the
+// convention used by ADF.Run.
+func (o *OnlineADF) Statistic() float64 {
+	k := o.Lag + 1
+	if o.rows <= k {
+		return 0
+	}
+
+	reg := mat.DenseCopyOf(o.xtx)
+	for i := 0; i < k; i++ {
+		reg.Set(i, i, reg.At(i, i)+LPenalty)
+	}
+
+	var beta mat.VecDense
+	beta.SolveVec(reg, o.xty)
+
+	var inv mat.Dense
+	if err := inv.Inverse(reg); err != nil {
+		return 0
+	}
+
+	// beta solves the ridge-penalized normal equations (X^T X + lambda I)
+	// beta = X^T y, not X^T X beta = X^T y, so beta^T X^T X beta =
+	// beta^T X^T y - lambda ||beta||^2 and the RSS correction below must
+	// account for the penalty term.
+	rss := o.yty - mat.Dot(&beta, o.xty) - LPenalty*mat.Dot(&beta, &beta)
+	sigma2 := rss / float64(o.rows-k)
+
+	sd0 := math.Sqrt(sigma2 * inv.At(0, 0))
+
+	return beta.AtVec(0) / sd0
+}
+
+// adfRow builds a single ADF regression row and its target from k+1
+// consecutive raw observations, following the same column layout as
+// fitADF's no-constant design matrix: [level, most-recent lag diff, ...,
+// oldest lag diff].
+func adfRow(w []float64, k int) ([]float64, float64) {
+	diffs := make([]float64, k)
+	for j := 0; j < k; j++ {
+		diffs[j] = w[j+1] - w[j]
+	}
+
+	row := make([]float64, k)
+	row[0] = w[k-1]
+	for j := 1; j < k; j++ {
+		row[j] = diffs[k-1-j]
+	}
+
+	return row, diffs[k-1]
+}
@@ -0,0 +1,64 @@
+package adf
+
+import "testing"
+
+func TestAdfRowLayout(t *testing.T) {
+	// k=2: w = [w0, w1, w2], diffs = [w1-w0, w2-w1].
+	// y is the most recent diff; row is [level, older diffs...].
+	row, y := adfRow([]float64{1, 3, 6}, 2)
+
+	wantRow := []float64{3, 2} // level=w1, older diff=w1-w0=2
+	wantY := 3.0               // most recent diff = w2-w1 = 3
+	for i := range wantRow {
+		if row[i] != wantRow[i] {
+			t.Errorf("row[%d] = %v, want %v", i, row[i], wantRow[i])
+		}
+	}
+	if y != wantY {
+		t.Errorf("y = %v, want %v", y, wantY)
+	}
+}
+
+func TestOnlineADFWindowRowWrapsCorrectly(t *testing.T) {
+	o := NewOnlineADF(4, 1)
+	for _, x := range []float64{10, 20, 30, 40, 50, 60} {
+		o.Push(x)
+	}
+
+	// After 6 pushes into a window of 4, the buffer holds [30, 40, 50, 60]
+	// regardless of how many times it has wrapped internally.
+	got := o.windowRow(0, 4)
+	want := []float64{30, 40, 50, 60}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("windowRow(0, 4)[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestOnlineADFMatchesBatchFitOnSameWindow(t *testing.T) {
+	series := []float64{1, 2, 1.5, 3, 2.5, 4, 3.2, 5}
+	window, lag := len(series), 1
+
+	o := NewOnlineADF(window, lag)
+	for _, x := range series {
+		o.Push(x)
+	}
+	onlineStat := o.Statistic()
+
+	ws := NewWorkspace()
+	result := fitADF(series, lag, None, RidgeEstimator, 0, ws)
+
+	// OnlineADF solves the same ridge-penalized normal equations as
+	// fitADF's RidgeEstimator path, so the two statistics should agree.
+	if diffAbs(onlineStat, result.statistic) > 1e-6 {
+		t.Errorf("OnlineADF.Statistic() = %v, fitADF gives %v", onlineStat, result.statistic)
+	}
+}
+
+func diffAbs(a, b float64) float64 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
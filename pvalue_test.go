@@ -0,0 +1,90 @@
+package adf
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLinearInterp(t *testing.T) {
+	got := linearInterp(0, 0, 10, 100, 5)
+	if got != 50 {
+		t.Errorf("linearInterp(0, 0, 10, 100, 5) = %v, want 50", got)
+	}
+}
+
+func TestQuadraticInterpAtNodes(t *testing.T) {
+	x0, y0 := -2.86, 0.05
+	x1, y1 := -1.62, 0.10
+	x2, y2 := -3.43, 0.01
+
+	for _, tt := range []struct{ x, want float64 }{
+		{x0, y0},
+		{x1, y1},
+		{x2, y2},
+	} {
+		got := quadraticInterp(x2, y2, x0, y0, x1, y1, tt.x)
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("quadraticInterp at node %v = %v, want %v", tt.x, got, tt.want)
+		}
+	}
+}
+
+func TestClampProb(t *testing.T) {
+	cases := []struct{ in, want float64 }{
+		{-0.5, 0},
+		{1.5, 1},
+		{0.3, 0.3},
+	}
+	for _, c := range cases {
+		if got := clampProb(c.in); got != c.want {
+			t.Errorf("clampProb(%v) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestPValueMonotonicInStatistic(t *testing.T) {
+	adf := ADF{Regression: Constant, Series: make([]float64, 50)}
+
+	adf.Statistic = -4.0
+	lowStat := adf.PValue()
+
+	adf.Statistic = -1.0
+	highStat := adf.PValue()
+
+	if !(lowStat < highStat) {
+		t.Errorf("PValue should increase as the statistic moves toward zero: got %v (stat=-4) >= %v (stat=-1)", lowStat, highStat)
+	}
+}
+
+func TestPValueAtCriticalValueMatchesLevel(t *testing.T) {
+	adf := ADF{Regression: Constant, Series: make([]float64, 50)}
+	cv := adf.CriticalValues(50)
+
+	adf.Statistic = cv["5%"]
+	if got := adf.PValue(); math.Abs(got-0.05) > 1e-9 {
+		t.Errorf("PValue at the 5%% critical value = %v, want 0.05", got)
+	}
+}
+
+func TestNewADFRejectsRawStatisticThreshold(t *testing.T) {
+	series := make([]float64, 10)
+	adf := NewADF(series, -3.45, 0)
+	if adf.PValueThreshold != DefaultPValue {
+		t.Errorf("NewADF with a raw-statistic pvalue should fall back to DefaultPValue, got %v", adf.PValueThreshold)
+	}
+}
+
+func TestIsStationaryUsesPValueThreshold(t *testing.T) {
+	adf := ADF{Regression: Constant, Series: make([]float64, 50), PValueThreshold: 0.05}
+	cv := adf.CriticalValues(50)
+
+	adf.Statistic = cv["1%"]
+	if !adf.IsStationary() {
+		t.Errorf("a statistic beyond the 1%% critical value should be stationary at PValueThreshold 0.05")
+	}
+
+	adf.Statistic = 0
+	if adf.IsStationary() {
+		t.Errorf("a statistic of 0 should not be stationary at PValueThreshold 0.05")
+	}
+}
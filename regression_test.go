@@ -0,0 +1,55 @@
+package adf
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDeterministicCols(t *testing.T) {
+	cases := map[RegressionMode]int{
+		None:          0,
+		Constant:      1,
+		ConstantTrend: 2,
+	}
+	for mode, want := range cases {
+		if got := deterministicCols(mode); got != want {
+			t.Errorf("deterministicCols(%v) = %d, want %d", mode, got, want)
+		}
+	}
+}
+
+func TestRegressionSeriesDemeansOnlyWhenNone(t *testing.T) {
+	series := []float64{1, 2, 3, 4, 5}
+
+	none := ADF{Series: series, Regression: None}
+	demeaned := none.regressionSeries()
+	var sum float64
+	for _, v := range demeaned {
+		sum += v
+	}
+	if sum > 1e-9 || sum < -1e-9 {
+		t.Errorf("regressionSeries with Regression: None should de-mean, got sum %v", sum)
+	}
+
+	constant := ADF{Series: series, Regression: Constant}
+	if got := constant.regressionSeries(); &got[0] != &series[0] {
+		t.Errorf("regressionSeries with Regression: Constant should return the original series unchanged")
+	}
+}
+
+func TestSetDeterministicColsTrend(t *testing.T) {
+	design := mat.NewDense(3, 3, nil)
+	setDeterministicCols(design, 1, ConstantTrend)
+
+	wantConstant := []float64{1, 1, 1}
+	wantTrend := []float64{1, 2, 3}
+	for i := 0; i < 3; i++ {
+		if design.At(i, 1) != wantConstant[i] {
+			t.Errorf("constant column row %d = %v, want %v", i, design.At(i, 1), wantConstant[i])
+		}
+		if design.At(i, 2) != wantTrend[i] {
+			t.Errorf("trend column row %d = %v, want %v", i, design.At(i, 2), wantTrend[i])
+		}
+	}
+}
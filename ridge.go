@@ -0,0 +1,83 @@
+package adf
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Ridge fits a ridge-penalized regression: the original estimator used by
+// this package before OLSEstimator/OLSHACEstimator were added. Its standard
+// errors are derived from the penalized normal equations' inverse rather
+// than the unpenalized (X^T X)^-1, so they're shrunk along with the
+// coefficients - see RidgeEstimator's doc comment for why this biases the
+// reported statistic.
+type Ridge struct {
+	design  *mat.Dense
+	y       *mat.VecDense
+	penalty float64
+
+	Coefficients *mat.VecDense // beta, set by Regress
+	StdErrs      []float64     // standard errors of Coefficients, set by Regress
+}
+
+// NewRidge creates and returns a new Ridge regression of y on design with
+// the given L2 penalty.
+func NewRidge(design *mat.Dense, y *mat.VecDense, penalty float64) *Ridge {
+	return &Ridge{design: design, y: y, penalty: penalty}
+}
+
+// Regress fits the ridge regression, solving (X^T X + penalty*I) beta = X^T y
+// and populating Coefficients and StdErrs.
+func (r *Ridge) Regress() {
+	n, c := r.design.Dims()
+
+	xtx := mat.NewDense(c, c, nil)
+	xty := mat.NewVecDense(c, nil)
+	for i := 0; i < c; i++ {
+		var sum float64
+		for t := 0; t < n; t++ {
+			sum += r.design.At(t, i) * r.y.AtVec(t)
+		}
+		xty.SetVec(i, sum)
+
+		for j := 0; j < c; j++ {
+			var s float64
+			for t := 0; t < n; t++ {
+				s += r.design.At(t, i) * r.design.At(t, j)
+			}
+			xtx.Set(i, j, s)
+		}
+	}
+
+	reg := mat.DenseCopyOf(xtx)
+	for i := 0; i < c; i++ {
+		reg.Set(i, i, reg.At(i, i)+r.penalty)
+	}
+
+	var beta mat.VecDense
+	beta.SolveVec(reg, xty)
+	r.Coefficients = &beta
+
+	fitted := mat.NewVecDense(n, nil)
+	fitted.MulVec(r.design, &beta)
+
+	resid := mat.NewVecDense(n, nil)
+	resid.SubVec(r.y, fitted)
+
+	rss := mat.Dot(resid, resid)
+	sigma2 := rss / float64(n-c)
+
+	var inv mat.Dense
+	sd := make([]float64, c)
+	if err := inv.Inverse(reg); err != nil {
+		for i := range sd {
+			sd[i] = math.NaN()
+		}
+	} else {
+		for i := 0; i < c; i++ {
+			sd[i] = math.Sqrt(sigma2 * inv.At(i, i))
+		}
+	}
+	r.StdErrs = sd
+}
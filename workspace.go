@@ -0,0 +1,45 @@
+package adf
+
+import "gonum.org/v1/gonum/mat"
+
+// Workspace holds scratch matrices reused across calls to fitADF. Series of
+// the same length and lag produce design and lagged-difference matrices of
+// identical shape, so passing the same Workspace to repeated calls lets
+// those allocations be amortised instead of paid for on every call. The
+// zero value is ready to use.
+type Workspace struct {
+	design *mat.Dense
+	z      *mat.Dense
+	fitted *mat.VecDense
+	resid  *mat.VecDense
+}
+
+// NewWorkspace returns an empty Workspace ready to be reused across calls
+// to fitADF, typically one per RunBatch worker.
+func NewWorkspace() *Workspace {
+	return &Workspace{}
+}
+
+// denseOf returns a *cur with the given shape, reallocating only if the
+// shape has changed since the last call.
+func (w *Workspace) denseOf(cur **mat.Dense, rows, cols int) *mat.Dense {
+	if *cur == nil {
+		*cur = mat.NewDense(rows, cols, nil)
+		return *cur
+	}
+
+	r, c := (*cur).Dims()
+	if r != rows || c != cols {
+		*cur = mat.NewDense(rows, cols, nil)
+	}
+	return *cur
+}
+
+// vecOf returns a *cur of the given length, reallocating only if the length
+// has changed since the last call.
+func (w *Workspace) vecOf(cur **mat.VecDense, n int) *mat.VecDense {
+	if *cur == nil || (*cur).Len() != n {
+		*cur = mat.NewVecDense(n, nil)
+	}
+	return *cur
+}